@@ -0,0 +1,44 @@
+package msgstream
+
+import (
+	"context"
+
+	kafka "github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+)
+
+// KafkaFactory builds KafkaMsgStream producers/consumers against a single
+// Kafka broker address.
+type KafkaFactory struct {
+	brokerAddr string
+	logger     *zap.Logger
+}
+
+// NewKafkaFactory returns a MsgStreamFactory backed by the Kafka broker at
+// brokerAddr.
+func NewKafkaFactory(brokerAddr string, lg *zap.Logger) *KafkaFactory {
+	return &KafkaFactory{brokerAddr: brokerAddr, logger: lg}
+}
+
+func (f *KafkaFactory) NewProducerStream(ctx context.Context, channels []string, bufSize int64) (MsgStream, error) {
+	stream := &KafkaMsgStream{ctx: ctx, bufSize: bufSize, logger: f.logger, writers: make(map[string]*kafka.Writer, len(channels))}
+	for _, topic := range channels {
+		stream.writers[topic] = &kafka.Writer{
+			Addr:     kafka.TCP(f.brokerAddr),
+			Topic:    topic,
+			Balancer: &kafka.LeastBytes{},
+		}
+	}
+	return stream, nil
+}
+
+func (f *KafkaFactory) NewConsumerStream(ctx context.Context, channels []string, bufSize int64) (MsgStream, error) {
+	stream := &KafkaMsgStream{ctx: ctx, bufSize: bufSize, logger: f.logger, readers: make(map[string]*kafka.Reader, len(channels))}
+	for _, topic := range channels {
+		stream.readers[topic] = kafka.NewReader(kafka.ReaderConfig{
+			Brokers: []string{f.brokerAddr},
+			Topic:   topic,
+		})
+	}
+	return stream, nil
+}
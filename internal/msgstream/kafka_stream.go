@@ -0,0 +1,75 @@
+package msgstream
+
+import (
+	"context"
+	"fmt"
+
+	kafka "github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+)
+
+// KafkaMsgStream is a MsgStream implementation backed by Kafka, used in
+// deployments where standing up a Pulsar cluster isn't viable.
+type KafkaMsgStream struct {
+	ctx     context.Context
+	bufSize int64
+	writers map[string]*kafka.Writer
+	readers map[string]*kafka.Reader
+	logger  *zap.Logger
+}
+
+func (ms *KafkaMsgStream) Start() {}
+
+func (ms *KafkaMsgStream) Close() {
+	for _, w := range ms.writers {
+		_ = w.Close()
+	}
+	for _, r := range ms.readers {
+		_ = r.Close()
+	}
+}
+
+// Produce publishes pack onto the Kafka topic configured for pack.Channel.
+func (ms *KafkaMsgStream) Produce(ctx context.Context, pack *MsgPack) error {
+	w, ok := ms.writers[pack.Channel]
+	if !ok {
+		return fmt.Errorf("kafka msgstream: no writer configured for channel %q", pack.Channel)
+	}
+	msgs := make([]kafka.Message, len(pack.Payload))
+	for i, payload := range pack.Payload {
+		msgs[i] = kafka.Message{Value: payload}
+	}
+	return w.WriteMessages(ctx, msgs...)
+}
+
+// Consume reads one message off whichever configured topic has one ready
+// first, blocking until ctx is cancelled if none do.
+func (ms *KafkaMsgStream) Consume(ctx context.Context) (*MsgPack, error) {
+	type result struct {
+		channel string
+		msg     kafka.Message
+		err     error
+	}
+
+	readCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	resCh := make(chan result, len(ms.readers))
+	for channel, r := range ms.readers {
+		go func(channel string, r *kafka.Reader) {
+			msg, err := r.ReadMessage(readCtx)
+			resCh <- result{channel: channel, msg: msg, err: err}
+		}(channel, r)
+	}
+
+	var res result
+	select {
+	case res = <-resCh:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if res.err != nil {
+		return nil, res.err
+	}
+	return &MsgPack{Channel: res.channel, Payload: [][]byte{res.msg.Value}}, nil
+}
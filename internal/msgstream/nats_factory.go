@@ -0,0 +1,64 @@
+package msgstream
+
+import (
+	"context"
+
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+)
+
+// NatsFactory builds NatsMsgStream producers/consumers against a single
+// NATS JetStream deployment.
+type NatsFactory struct {
+	url    string
+	logger *zap.Logger
+}
+
+// NewNatsFactory returns a MsgStreamFactory backed by the NATS server at url.
+func NewNatsFactory(url string, lg *zap.Logger) *NatsFactory {
+	return &NatsFactory{url: url, logger: lg}
+}
+
+func (f *NatsFactory) connect() (*nats.Conn, nats.JetStreamContext, error) {
+	conn, err := nats.Connect(f.url)
+	if err != nil {
+		return nil, nil, err
+	}
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	return conn, js, nil
+}
+
+func (f *NatsFactory) NewProducerStream(ctx context.Context, channels []string, bufSize int64) (MsgStream, error) {
+	conn, js, err := f.connect()
+	if err != nil {
+		return nil, err
+	}
+	for _, subject := range channels {
+		if _, err := js.AddStream(&nats.StreamConfig{Name: subject, Subjects: []string{subject}}); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return &NatsMsgStream{ctx: ctx, bufSize: bufSize, conn: conn, js: js, logger: f.logger}, nil
+}
+
+func (f *NatsFactory) NewConsumerStream(ctx context.Context, channels []string, bufSize int64) (MsgStream, error) {
+	conn, js, err := f.connect()
+	if err != nil {
+		return nil, err
+	}
+	stream := &NatsMsgStream{ctx: ctx, bufSize: bufSize, conn: conn, js: js, logger: f.logger, consumers: make(map[string]*nats.Subscription, len(channels))}
+	for _, subject := range channels {
+		sub, err := js.PullSubscribe(subject, "")
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		stream.consumers[subject] = sub
+	}
+	return stream, nil
+}
@@ -0,0 +1,88 @@
+package msgstream
+
+import (
+	"context"
+
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+)
+
+// NatsMsgStream is a MsgStream implementation backed by NATS JetStream,
+// used in deployments where standing up a Pulsar cluster isn't viable.
+type NatsMsgStream struct {
+	ctx       context.Context
+	bufSize   int64
+	conn      *nats.Conn
+	js        nats.JetStreamContext
+	consumers map[string]*nats.Subscription
+	logger    *zap.Logger
+}
+
+func (ms *NatsMsgStream) Start() {}
+
+func (ms *NatsMsgStream) Close() {
+	for _, sub := range ms.consumers {
+		_ = sub.Unsubscribe()
+	}
+	if ms.conn != nil {
+		ms.conn.Close()
+	}
+}
+
+// Produce publishes pack onto its channel's JetStream subject.
+func (ms *NatsMsgStream) Produce(ctx context.Context, pack *MsgPack) error {
+	for _, payload := range pack.Payload {
+		if _, err := ms.js.Publish(pack.Channel, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Consume fans a goroutine out per subscribed subject and returns whichever
+// one has a message ready first, blocking until ctx is cancelled if none do.
+// Mirrors KafkaMsgStream.Consume so multi-channel latency doesn't depend on
+// which backend a deployment picked.
+func (ms *NatsMsgStream) Consume(ctx context.Context) (*MsgPack, error) {
+	type result struct {
+		channel string
+		msg     *nats.Msg
+		err     error
+	}
+
+	fetchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	resCh := make(chan result, len(ms.consumers))
+	for subject, sub := range ms.consumers {
+		go func(subject string, sub *nats.Subscription) {
+			for {
+				if fetchCtx.Err() != nil {
+					resCh <- result{err: fetchCtx.Err()}
+					return
+				}
+				msgs, err := sub.Fetch(1, nats.Context(fetchCtx))
+				if err != nil {
+					resCh <- result{err: err}
+					return
+				}
+				if len(msgs) > 0 {
+					resCh <- result{channel: subject, msg: msgs[0]}
+					return
+				}
+			}
+		}(subject, sub)
+	}
+
+	var res result
+	select {
+	case res = <-resCh:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if res.err != nil {
+		return nil, res.err
+	}
+	_ = res.msg.Ack()
+	return &MsgPack{Channel: res.channel, Payload: [][]byte{res.msg.Data}}, nil
+}
@@ -0,0 +1,35 @@
+package msgstream
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// PulsarFactory builds PulsarMsgStream producers/consumers against a single
+// Pulsar cluster address, preserving the behavior Proxy relied on before the
+// MsgStreamFactory abstraction existed.
+type PulsarFactory struct {
+	address string
+	logger  *zap.Logger
+}
+
+// NewPulsarFactory returns a MsgStreamFactory backed by the Pulsar cluster at
+// address.
+func NewPulsarFactory(address string, lg *zap.Logger) *PulsarFactory {
+	return &PulsarFactory{address: address, logger: lg}
+}
+
+func (f *PulsarFactory) NewProducerStream(ctx context.Context, channels []string, bufSize int64) (MsgStream, error) {
+	stream := NewPulsarMsgStream(ctx, bufSize, f.logger)
+	stream.SetPulsarClient(f.address)
+	stream.CreatePulsarProducers(channels)
+	return stream, nil
+}
+
+func (f *PulsarFactory) NewConsumerStream(ctx context.Context, channels []string, bufSize int64) (MsgStream, error) {
+	stream := NewPulsarMsgStream(ctx, bufSize, f.logger)
+	stream.SetPulsarClient(f.address)
+	stream.CreatePulsarConsumers(channels)
+	return stream, nil
+}
@@ -0,0 +1,46 @@
+package proxy
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/zilliztech/milvus-distributed/internal/proxy/metrics"
+)
+
+// unaryServerInterceptor times each MilvusService RPC into
+// metrics.GRPCRequestLatency/GRPCRequestTotal and wraps the handler in an
+// OpenTelemetry span, recording the error on the span when the handler fails.
+func (p *Proxy) unaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, span := p.tracer.Start(ctx, info.FullMethod)
+		defer span.End()
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		metrics.GRPCRequestLatency.WithLabelValues(info.FullMethod).Observe(time.Since(start).Seconds())
+		metrics.GRPCRequestTotal.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+		if err != nil {
+			span.RecordError(err)
+		}
+
+		return resp, err
+	}
+}
+
+// sampleSchedulerQueueDepth periodically publishes Proxy.InFlight into the
+// scheduler_queue_depth gauge until ctx is cancelled.
+func (p *Proxy) sampleSchedulerQueueDepth(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			metrics.SchedulerQueueDepth.Set(float64(p.InFlight()))
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/zilliztech/milvus-distributed/internal/proto/masterpb"
+)
+
+// MasterResolver discovers the set of master endpoints a Proxy should
+// maintain connections to. Implementations can back this with etcd watches
+// or DNS SRV lookups instead of the static list used by default.
+type MasterResolver interface {
+	Resolve(ctx context.Context) ([]string, error)
+}
+
+// staticMasterResolver always resolves to the fixed list of addresses it was
+// built with, preserving the previous single-address behavior when no
+// resolver is configured.
+type staticMasterResolver struct {
+	addrs []string
+}
+
+func (r *staticMasterResolver) Resolve(ctx context.Context) ([]string, error) {
+	return r.addrs, nil
+}
+
+// masterEndpoint tracks one candidate master connection and its last known
+// health status.
+type masterEndpoint struct {
+	addr    string
+	conn    *grpc.ClientConn
+	healthy bool
+}
+
+// masterPool dials every endpoint returned by a MasterResolver, probes them
+// periodically via grpc_health_v1, and elects the first healthy endpoint as
+// leader. Proxy reads the leader connection through leaderConn/leaderAddr
+// and is notified of leader changes so it can re-point its allocators.
+type masterPool struct {
+	mu        sync.RWMutex
+	resolver  MasterResolver
+	endpoints []*masterEndpoint
+	leader    *masterEndpoint
+
+	probeInterval time.Duration
+	probeTimeout  time.Duration
+
+	onLeaderChange func(addr string)
+	logger         *zap.Logger
+
+	tlsConfig TLSConfig
+	certStore *certStore
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+func newMasterPool(resolver MasterResolver, lg *zap.Logger, onLeaderChange func(addr string), tlsConfig TLSConfig, store *certStore) *masterPool {
+	return &masterPool{
+		resolver:       resolver,
+		probeInterval:  5 * time.Second,
+		probeTimeout:   2 * time.Second,
+		onLeaderChange: onLeaderChange,
+		logger:         lg,
+		tlsConfig:      tlsConfig,
+		certStore:      store,
+		stopCh:         make(chan struct{}),
+	}
+}
+
+// Start resolves the initial endpoint set, dials each candidate, and elects
+// a leader before returning. It then launches a background health-probe loop.
+func (mp *masterPool) Start(ctx context.Context) error {
+	addrs, err := mp.resolver.Resolve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to resolve master endpoints: %w", err)
+	}
+	if len(addrs) == 0 {
+		return fmt.Errorf("master resolver returned no endpoints")
+	}
+
+	for _, addr := range addrs {
+		ep, err := mp.dial(ctx, addr)
+		if err != nil {
+			mp.logger.Warn("failed to dial master candidate", zap.String("addr", addr), zap.Error(err))
+			continue
+		}
+		mp.endpoints = append(mp.endpoints, ep)
+	}
+	if len(mp.endpoints) == 0 {
+		return fmt.Errorf("unable to connect to any master endpoint in %v", addrs)
+	}
+
+	mp.electLeader()
+
+	mp.wg.Add(1)
+	go mp.probeLoop()
+
+	return nil
+}
+
+func (mp *masterPool) dial(ctx context.Context, addr string) (*masterEndpoint, error) {
+	dialCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	dialOpts := []grpc.DialOption{grpc.WithBlock(), grpc.WithUnaryInterceptor(mp.failoverUnaryInterceptor())}
+	if mp.tlsConfig.Enabled() {
+		creds := credentials.NewTLS(mp.tlsConfig.clientTLSConfig(mp.certStore))
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(creds))
+	} else {
+		dialOpts = append(dialOpts, grpc.WithInsecure())
+	}
+
+	conn, err := grpc.DialContext(dialCtx, addr, dialOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return &masterEndpoint{addr: addr, conn: conn, healthy: true}, nil
+}
+
+func (mp *masterPool) probeLoop() {
+	defer mp.wg.Done()
+	ticker := time.NewTicker(mp.probeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-mp.stopCh:
+			return
+		case <-ticker.C:
+			mp.probeOnce()
+		}
+	}
+}
+
+func (mp *masterPool) probeOnce() {
+	mp.mu.Lock()
+	endpoints := append([]*masterEndpoint(nil), mp.endpoints...)
+	mp.mu.Unlock()
+
+	for _, ep := range endpoints {
+		ctx, cancel := context.WithTimeout(context.Background(), mp.probeTimeout)
+		healthy := mp.check(ctx, ep)
+		cancel()
+
+		mp.mu.Lock()
+		ep.healthy = healthy
+		mp.mu.Unlock()
+	}
+
+	mp.mu.Lock()
+	needsElection := mp.leader == nil || !mp.leader.healthy
+	mp.mu.Unlock()
+	if needsElection {
+		mp.electLeader()
+	}
+}
+
+func (mp *masterPool) check(ctx context.Context, ep *masterEndpoint) bool {
+	client := healthpb.NewHealthClient(ep.conn)
+	resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		return false
+	}
+	return resp.GetStatus() == healthpb.HealthCheckResponse_SERVING
+}
+
+// electLeader picks the first healthy endpoint and, if it differs from the
+// current leader, notifies onLeaderChange so Proxy can re-point its
+// allocators at the new address.
+func (mp *masterPool) electLeader() {
+	mp.mu.Lock()
+	var newLeader *masterEndpoint
+	for _, ep := range mp.endpoints {
+		if ep.healthy {
+			newLeader = ep
+			break
+		}
+	}
+	changed := newLeader != nil && newLeader != mp.leader
+	mp.leader = newLeader
+	cb := mp.onLeaderChange
+	mp.mu.Unlock()
+
+	if changed && cb != nil {
+		cb(newLeader.addr)
+	}
+}
+
+// failover marks the current leader unhealthy and elects the next candidate,
+// used when an RPC against the leader fails with Unavailable/DeadlineExceeded.
+func (mp *masterPool) failover() {
+	mp.mu.Lock()
+	if mp.leader != nil {
+		mp.leader.healthy = false
+	}
+	mp.mu.Unlock()
+	mp.electLeader()
+}
+
+// failoverUnaryInterceptor is attached to every dialed connection so that any
+// RPC made through it - regardless of which masterpb.MasterClient method was
+// promoted via failoverMasterClient's embedding - transparently retries
+// against the next healthy endpoint on Unavailable/DeadlineExceeded, instead
+// of relying on a hand-written wrapper callers could bypass.
+func (mp *masterPool) failoverUnaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if err == nil {
+			return nil
+		}
+		code := status.Code(err)
+		if code != codes.Unavailable && code != codes.DeadlineExceeded {
+			return err
+		}
+
+		mp.failover()
+		conn, cerr := mp.leaderConn()
+		if cerr != nil || conn == cc {
+			return err
+		}
+		return conn.Invoke(ctx, method, req, reply, opts...)
+	}
+}
+
+func (mp *masterPool) leaderConn() (*grpc.ClientConn, error) {
+	mp.mu.RLock()
+	defer mp.mu.RUnlock()
+	if mp.leader == nil {
+		return nil, fmt.Errorf("no healthy master endpoint available")
+	}
+	return mp.leader.conn, nil
+}
+
+func (mp *masterPool) leaderAddr() string {
+	mp.mu.RLock()
+	defer mp.mu.RUnlock()
+	if mp.leader == nil {
+		return ""
+	}
+	return mp.leader.addr
+}
+
+func (mp *masterPool) Close() {
+	close(mp.stopCh)
+	mp.wg.Wait()
+
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	for _, ep := range mp.endpoints {
+		_ = ep.conn.Close()
+	}
+}
+
+// failoverMasterClient wraps masterpb.MasterClient so that every generated
+// RPC method promoted through the embedded interface dials the pool's
+// current leader connection, which already carries a failoverUnaryInterceptor
+// - the actual retry-on-Unavailable/DeadlineExceeded logic lives there, not
+// in this type, so a caller can never bypass it by reaching for a raw method.
+type failoverMasterClient struct {
+	masterpb.MasterClient
+	pool *masterPool
+}
+
+func newFailoverMasterClient(pool *masterPool) *failoverMasterClient {
+	c := &failoverMasterClient{pool: pool}
+	c.refresh()
+	return c
+}
+
+// refresh re-binds the embedded MasterClient to the pool's current leader
+// connection. It is called once at construction and again from
+// Proxy.onLeaderChange whenever the pool elects a new leader, so in-flight
+// callers are never left pointed at a stale connection.
+func (c *failoverMasterClient) refresh() {
+	conn, err := c.pool.leaderConn()
+	if err != nil {
+		return
+	}
+	c.MasterClient = masterpb.NewMasterClient(conn)
+}
@@ -0,0 +1,72 @@
+package proxy
+
+import "testing"
+
+func TestStaticMasterResolverResolve(t *testing.T) {
+	want := []string{"a:1", "b:2"}
+	r := &staticMasterResolver{addrs: want}
+
+	got, err := r.Resolve(nil)
+	if err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("Resolve() = %v, want %v", got, want)
+	}
+}
+
+func TestMasterPoolElectLeaderPrefersFirstHealthy(t *testing.T) {
+	unhealthy := &masterEndpoint{addr: "down:1", healthy: false}
+	healthy := &masterEndpoint{addr: "up:2", healthy: true}
+
+	var changedTo string
+	mp := &masterPool{
+		endpoints:      []*masterEndpoint{unhealthy, healthy},
+		onLeaderChange: func(addr string) { changedTo = addr },
+	}
+
+	mp.electLeader()
+
+	if mp.leader != healthy {
+		t.Fatalf("electLeader() picked %v, want the healthy endpoint", mp.leader)
+	}
+	if changedTo != healthy.addr {
+		t.Fatalf("onLeaderChange called with %q, want %q", changedTo, healthy.addr)
+	}
+}
+
+func TestMasterPoolElectLeaderNoChangeNoCallback(t *testing.T) {
+	ep := &masterEndpoint{addr: "up:1", healthy: true}
+
+	calls := 0
+	mp := &masterPool{
+		endpoints:      []*masterEndpoint{ep},
+		leader:         ep,
+		onLeaderChange: func(string) { calls++ },
+	}
+
+	mp.electLeader()
+
+	if calls != 0 {
+		t.Fatalf("onLeaderChange called %d times, want 0 when the leader is unchanged", calls)
+	}
+}
+
+func TestMasterPoolFailoverMarksLeaderUnhealthyAndElectsNext(t *testing.T) {
+	first := &masterEndpoint{addr: "a:1", healthy: true}
+	second := &masterEndpoint{addr: "b:2", healthy: true}
+
+	mp := &masterPool{
+		endpoints: []*masterEndpoint{first, second},
+		leader:    first,
+	}
+
+	mp.failover()
+
+	if first.healthy {
+		t.Fatalf("failover() left the old leader marked healthy")
+	}
+	if mp.leader != second {
+		t.Fatalf("failover() elected %v, want the remaining healthy endpoint", mp.leader)
+	}
+}
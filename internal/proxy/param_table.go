@@ -0,0 +1,72 @@
+package proxy
+
+import (
+	"os"
+	"strings"
+
+	"github.com/zilliztech/milvus-distributed/internal/msgstream"
+)
+
+// paramTable is a minimal stand-in for the real configuration loader: it
+// reads Proxy settings from environment variables, falling back to sane
+// defaults so a bare `go run` still comes up against a local Pulsar/master.
+// Params is the package-level instance every constructor reads from.
+type paramTable struct{}
+
+// Params is threaded through the Proxy constructors and Reload.
+var Params paramTable
+
+// InitParamTable is a placeholder for the real config-loading step (e.g.
+// reading a YAML file); the getters below read the environment lazily so
+// Reload always sees the current process environment without needing an
+// explicit re-init.
+func (pt *paramTable) InitParamTable() {}
+
+func getEnv(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// PulsarAddress returns the Pulsar cluster address used by the default
+// Pulsar-backed MsgStreamFactory.
+func (pt *paramTable) PulsarAddress() string {
+	return getEnv("PULSAR_ADDRESS", "pulsar://localhost:6650")
+}
+
+// MasterAddresses returns the master endpoints the masterPool dials,
+// configured as a comma-separated list via MASTER_ADDRESS.
+func (pt *paramTable) MasterAddresses() []string {
+	return strings.Split(getEnv("MASTER_ADDRESS", "localhost:19530"), ",")
+}
+
+// MsgStreamType selects the MsgStreamFactory backend (pulsar, kafka, nats).
+func (pt *paramTable) MsgStreamType() string {
+	return getEnv("MSG_STREAM_TYPE", msgstream.BackendPulsar)
+}
+
+// TLSConfig reads the Proxy's TLS/mTLS material from environment variables.
+// TLSConfig.Enabled() is false (falling back to cleartext) unless both
+// PROXY_TLS_CERT and PROXY_TLS_KEY are set.
+func (pt *paramTable) TLSConfig() TLSConfig {
+	return TLSConfig{
+		CertFile:          os.Getenv("PROXY_TLS_CERT"),
+		KeyFile:           os.Getenv("PROXY_TLS_KEY"),
+		CAFile:            os.Getenv("PROXY_TLS_CA"),
+		RequireClientCert: os.Getenv("PROXY_TLS_REQUIRE_CLIENT_CERT") == "true",
+	}
+}
+
+// MetricsAddress returns the listen address for the Prometheus /metrics
+// endpoint, separate from the gRPC port.
+func (pt *paramTable) MetricsAddress() string {
+	return getEnv("PROXY_METRICS_ADDRESS", ":9091")
+}
+
+// TracingEndpoint returns the OTLP collector address spans are exported to.
+// Empty disables remote export and falls back to a stdout exporter, which is
+// what local development runs against.
+func (pt *paramTable) TracingEndpoint() string {
+	return os.Getenv("PROXY_TRACING_ENDPOINT")
+}
@@ -2,17 +2,20 @@ package proxy
 
 import (
 	"context"
-	"log"
+	"fmt"
 	"math/rand"
 	"net"
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
 	"github.com/zilliztech/milvus-distributed/internal/allocator"
 	"github.com/zilliztech/milvus-distributed/internal/msgstream"
-	"github.com/zilliztech/milvus-distributed/internal/proto/masterpb"
+	"github.com/zilliztech/milvus-distributed/internal/proxy/metrics"
 	"github.com/zilliztech/milvus-distributed/internal/proto/servicepb"
 	"github.com/zilliztech/milvus-distributed/internal/util/typeutil"
 )
@@ -26,8 +29,11 @@ type Proxy struct {
 	proxyLoopWg     sync.WaitGroup
 
 	grpcServer   *grpc.Server
-	masterConn   *grpc.ClientConn
-	masterClient masterpb.MasterClient
+	grpcListener net.Listener
+	tlsConfig    TLSConfig
+	certStore    *certStore
+	masterPool   *masterPool
+	masterClient *failoverMasterClient
 	sched        *TaskScheduler
 	tick         *timeTick
 
@@ -35,78 +41,219 @@ type Proxy struct {
 	tsoAllocator *allocator.TimestampAllocator
 	segAssigner  *allocator.SegIDAssigner
 
-	manipulationMsgStream *msgstream.PulsarMsgStream
-	queryMsgStream        *msgstream.PulsarMsgStream
+	msgStreamFactory      msgstream.MsgStreamFactory
+	manipulationMsgStream msgstream.MsgStream
+	queryMsgStream        msgstream.MsgStream
 
 	// Add callback functions at different stages
-	startCallbacks []func()
-	closeCallbacks []func()
+	startCallbacks        []func()
+	closeCallbacks        []func()
+	leaderChangeCallbacks []func(addr string)
+
+	logger *zap.Logger
+
+	tracer         trace.Tracer
+	tracerShutdown func(context.Context) error
+	metricsServer  *metrics.Server
 }
 
 func Init() {
 	Params.InitParamTable()
 }
 
+// CreateProxy builds a Proxy with a default production zap logger.
 func CreateProxy(ctx context.Context) (*Proxy, error) {
+	lg, err := zap.NewProduction()
+	if err != nil {
+		return nil, err
+	}
+	return CreateProxyWithLogger(ctx, lg)
+}
+
+// CreateProxyWithLogger builds a Proxy using the supplied logger, allowing
+// callers (and tests) to control the log sink and level instead of always
+// writing production JSON logs to stderr. The master pool is resolved from
+// the static address list in Params; use CreateProxyWithResolver to plug in
+// etcd- or DNS-based discovery instead.
+func CreateProxyWithLogger(ctx context.Context, lg *zap.Logger) (*Proxy, error) {
+	return CreateProxyWithResolver(ctx, lg, &staticMasterResolver{addrs: Params.MasterAddresses()})
+}
+
+// CreateProxyWithResolver builds a Proxy whose master pool is populated by
+// the given MasterResolver instead of the static address list, so operators
+// can back master discovery with etcd watches or DNS SRV lookups.
+func CreateProxyWithResolver(ctx context.Context, lg *zap.Logger, resolver MasterResolver) (*Proxy, error) {
 	rand.Seed(time.Now().UnixNano())
 	ctx1, cancel := context.WithCancel(ctx)
+	lg = lg.With(zap.String("component", "proxy"))
 	p := &Proxy{
 		proxyLoopCtx:    ctx1,
 		proxyLoopCancel: cancel,
+		logger:          lg,
+	}
+
+	p.tlsConfig = Params.TLSConfig()
+	p.certStore = newCertStore()
+	if p.tlsConfig.Enabled() {
+		if err := p.certStore.load(p.tlsConfig); err != nil {
+			return nil, fmt.Errorf("refusing to start with invalid TLS config: %w", err)
+		}
+	} else {
+		lg.Warn("proxy TLS is not configured, gRPC traffic to clients and master will be cleartext")
+	}
+
+	tracer, tracerShutdown, err := initTracer(Params.TracingEndpoint())
+	if err != nil {
+		return nil, err
 	}
+	p.tracer = tracer
+	p.tracerShutdown = tracerShutdown
+	p.metricsServer = metrics.NewServer(Params.MetricsAddress())
 
 	// TODO: use config instead
-	pulsarAddress := Params.PulsarAddress()
 	bufSize := int64(1000)
 	manipulationChannels := []string{"manipulation"}
 	queryChannels := []string{"query"}
 
-	p.manipulationMsgStream = msgstream.NewPulsarMsgStream(p.proxyLoopCtx, bufSize)
-	p.manipulationMsgStream.SetPulsarClient(pulsarAddress)
-	p.manipulationMsgStream.CreatePulsarProducers(manipulationChannels)
-
-	p.queryMsgStream = msgstream.NewPulsarMsgStream(p.proxyLoopCtx, bufSize)
-	p.queryMsgStream.SetPulsarClient(pulsarAddress)
-	p.queryMsgStream.CreatePulsarProducers(queryChannels)
-
-	masterAddr := Params.MasterAddress()
-	idAllocator, err := allocator.NewIDAllocator(p.proxyLoopCtx, masterAddr)
-
+	msgStreamFactory, err := msgstream.NewMsgStreamFactory(Params.MsgStreamType(), Params.PulsarAddress(), p.logger)
 	if err != nil {
 		return nil, err
 	}
-	p.idAllocator = idAllocator
+	p.msgStreamFactory = msgStreamFactory
 
-	tsoAllocator, err := allocator.NewTimestampAllocator(p.proxyLoopCtx, masterAddr)
+	p.manipulationMsgStream, err = p.msgStreamFactory.NewProducerStream(p.proxyLoopCtx, manipulationChannels, bufSize)
 	if err != nil {
 		return nil, err
 	}
-	p.tsoAllocator = tsoAllocator
 
-	segAssigner, err := allocator.NewSegIDAssigner(p.proxyLoopCtx, masterAddr)
+	p.queryMsgStream, err = p.msgStreamFactory.NewProducerStream(p.proxyLoopCtx, queryChannels, bufSize)
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+
+	p.masterPool = newMasterPool(resolver, p.logger, p.onLeaderChange, p.tlsConfig, p.certStore)
+	if err := p.masterPool.Start(p.proxyLoopCtx); err != nil {
+		return nil, err
+	}
+	p.masterClient = newFailoverMasterClient(p.masterPool)
+
+	if err := p.repointAllocators(p.masterPool.leaderAddr()); err != nil {
+		return nil, err
 	}
-	p.segAssigner = segAssigner
 
-	p.sched, err = NewTaskScheduler(p.proxyLoopCtx, p.idAllocator, p.tsoAllocator)
+	sched, err := NewTaskScheduler(p.proxyLoopCtx, p.idAllocator, p.tsoAllocator, p.logger)
 	if err != nil {
 		return nil, err
 	}
+	p.sched = sched
 
 	return p, nil
 }
 
+// AddLeaderChangeCallback registers a callback invoked whenever the master
+// pool elects a new leader, mirroring the start/close callback mechanism.
+func (p *Proxy) AddLeaderChangeCallback(callbacks ...func(addr string)) {
+	p.leaderChangeCallbacks = append(p.leaderChangeCallbacks, callbacks...)
+}
+
+// onLeaderChange re-points the allocators and the master client at the newly
+// elected leader, then notifies registered callbacks.
+func (p *Proxy) onLeaderChange(addr string) {
+	p.logger.Info("master leader changed", zap.String("master_addr", addr))
+	if p.masterClient != nil {
+		p.masterClient.refresh()
+	}
+	if err := p.repointAllocators(addr); err != nil {
+		p.logger.Error("failed to re-point allocators to new leader", zap.String("master_addr", addr), zap.Error(err))
+	}
+	for _, cb := range p.leaderChangeCallbacks {
+		cb(addr)
+	}
+}
+
+// repointAllocators rebuilds the ID/timestamp/segment allocators against the
+// given master address. All three are built before any field is replaced, so
+// a failure leaves the previous allocators in place; the fields are then
+// reassigned one by one (not atomically) and the previous allocators closed.
+func (p *Proxy) repointAllocators(masterAddr string) error {
+	idStart := time.Now()
+	idAllocator, err := allocator.NewIDAllocator(p.proxyLoopCtx, masterAddr, p.logger)
+	metrics.AllocatorRequestLatency.WithLabelValues("id").Observe(time.Since(idStart).Seconds())
+	if err != nil {
+		metrics.AllocatorRequestErrors.WithLabelValues("id").Inc()
+		return err
+	}
+
+	tsoStart := time.Now()
+	tsoAllocator, err := allocator.NewTimestampAllocator(p.proxyLoopCtx, masterAddr, p.logger)
+	metrics.AllocatorRequestLatency.WithLabelValues("timestamp").Observe(time.Since(tsoStart).Seconds())
+	if err != nil {
+		metrics.AllocatorRequestErrors.WithLabelValues("timestamp").Inc()
+		return err
+	}
+
+	segStart := time.Now()
+	segAssigner, err := allocator.NewSegIDAssigner(p.proxyLoopCtx, masterAddr, p.logger)
+	metrics.AllocatorRequestLatency.WithLabelValues("segment").Observe(time.Since(segStart).Seconds())
+	if err != nil {
+		metrics.AllocatorRequestErrors.WithLabelValues("segment").Inc()
+		return err
+	}
+
+	oldIDAllocator, oldTsoAllocator, oldSegAssigner := p.idAllocator, p.tsoAllocator, p.segAssigner
+
+	p.idAllocator = idAllocator
+	p.tsoAllocator = tsoAllocator
+	p.segAssigner = segAssigner
+
+	// Close whatever allocators this call is superseding regardless of
+	// whether the scheduler is running yet: the initial election inside
+	// masterPool.Start fires this via onLeaderChange before p.sched exists,
+	// and CreateProxyWithResolver repoints again right after - without this,
+	// that first allocator set (and its live grpc connections to master)
+	// would be silently discarded without ever being closed.
+	if oldIDAllocator != nil {
+		oldIDAllocator.Close()
+	}
+	if oldTsoAllocator != nil {
+		oldTsoAllocator.Close()
+	}
+	if oldSegAssigner != nil {
+		oldSegAssigner.Close()
+	}
+
+	if p.sched != nil {
+		// The scheduler is already running against the previous allocators;
+		// start the replacements now so in-flight requests see them
+		// immediately. Before the scheduler exists, startProxyWithListener
+		// is responsible for the initial Start() call.
+		p.idAllocator.Start()
+		p.tsoAllocator.Start()
+		p.segAssigner.Start()
+	}
+
+	return nil
+}
+
 // AddStartCallback adds a callback in the startServer phase.
 func (p *Proxy) AddStartCallback(callbacks ...func()) {
 	p.startCallbacks = append(p.startCallbacks, callbacks...)
 }
 
 func (p *Proxy) startProxy() error {
-	err := p.connectMaster()
+	// TODO: use address in config instead
+	lis, err := net.Listen("tcp", ":5053")
 	if err != nil {
 		return err
 	}
+	return p.startProxyWithListener(lis)
+}
+
+// startProxyWithListener runs the normal startup sequence but serves gRPC on
+// an externally supplied listener instead of binding a fresh one. This lets a
+// supervising process hand the Proxy a listener it inherited from a prior
+// process (e.g. across a SIGUSR2 live-upgrade) so no connections are dropped.
+func (p *Proxy) startProxyWithListener(lis net.Listener) error {
 	initGlobalMetaCache(p.proxyLoopCtx, p.masterClient, p.idAllocator, p.tsoAllocator)
 	p.manipulationMsgStream.Start()
 	p.queryMsgStream.Start()
@@ -115,11 +262,15 @@ func (p *Proxy) startProxy() error {
 	p.tsoAllocator.Start()
 	p.segAssigner.Start()
 
+	p.metricsServer.Start()
+	go p.sampleSchedulerQueueDepth(p.proxyLoopCtx)
+
 	// Start callbacks
 	for _, cb := range p.startCallbacks {
 		cb()
 	}
 
+	p.grpcListener = lis
 	p.proxyLoopWg.Add(1)
 	go p.grpcLoop()
 
@@ -134,37 +285,87 @@ func (p *Proxy) AddCloseCallback(callbacks ...func()) {
 func (p *Proxy) grpcLoop() {
 	defer p.proxyLoopWg.Done()
 
-	// TODO: use address in config instead
-	lis, err := net.Listen("tcp", ":5053")
-	if err != nil {
-		log.Fatalf("Proxy grpc server fatal error=%v", err)
+	serverOpts := []grpc.ServerOption{grpc.UnaryInterceptor(p.unaryServerInterceptor())}
+	if p.tlsConfig.Enabled() {
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(p.tlsConfig.serverTLSConfig(p.certStore))))
 	}
 
-	p.grpcServer = grpc.NewServer()
+	p.grpcServer = grpc.NewServer(serverOpts...)
 	servicepb.RegisterMilvusServiceServer(p.grpcServer, p)
-	if err = p.grpcServer.Serve(lis); err != nil {
-		log.Fatalf("Proxy grpc server fatal error=%v", err)
+	if err := p.grpcServer.Serve(p.grpcListener); err != nil {
+		p.logger.Error("proxy grpc server stopped serving", zap.Error(err))
 	}
 }
 
-func (p *Proxy) connectMaster() error {
-	masterAddr := Params.MasterAddress()
-	log.Printf("Proxy connected to master, master_addr=%s", masterAddr)
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	conn, err := grpc.DialContext(ctx, masterAddr, grpc.WithInsecure(), grpc.WithBlock())
+func (p *Proxy) Start() error {
+	return p.startProxy()
+}
+
+// StartWithListener is like Start but serves gRPC on the given listener,
+// e.g. one inherited from a parent process across a live reload.
+func (p *Proxy) StartWithListener(lis net.Listener) error {
+	return p.startProxyWithListener(lis)
+}
+
+// Reload re-reads Params and rotates the msg streams and master pool onto
+// the new configuration without tearing down the process. It is invoked by
+// the supervisor on SIGHUP.
+func (p *Proxy) Reload() error {
+	Params.InitParamTable()
+
+	p.tlsConfig = Params.TLSConfig()
+	if p.tlsConfig.Enabled() {
+		if err := p.certStore.load(p.tlsConfig); err != nil {
+			return fmt.Errorf("refusing to reload invalid TLS config: %w", err)
+		}
+	}
+
+	manipulationChannels := []string{"manipulation"}
+	queryChannels := []string{"query"}
+
+	msgStreamFactory, err := msgstream.NewMsgStreamFactory(Params.MsgStreamType(), Params.PulsarAddress(), p.logger)
 	if err != nil {
-		log.Printf("Proxy connect to master failed, error= %v", err)
 		return err
 	}
-	log.Printf("Proxy connected to master, master_addr=%s", masterAddr)
-	p.masterConn = conn
-	p.masterClient = masterpb.NewMasterClient(conn)
+	p.msgStreamFactory = msgStreamFactory
+
+	manipulationMsgStream, err := p.msgStreamFactory.NewProducerStream(p.proxyLoopCtx, manipulationChannels, 1000)
+	if err != nil {
+		return err
+	}
+	p.manipulationMsgStream.Close()
+	p.manipulationMsgStream = manipulationMsgStream
+	p.manipulationMsgStream.Start()
+
+	queryMsgStream, err := p.msgStreamFactory.NewProducerStream(p.proxyLoopCtx, queryChannels, 1000)
+	if err != nil {
+		return err
+	}
+	p.queryMsgStream.Close()
+	p.queryMsgStream = queryMsgStream
+	p.queryMsgStream.Start()
+
+	oldPool := p.masterPool
+	newPool := newMasterPool(&staticMasterResolver{addrs: Params.MasterAddresses()}, p.logger, p.onLeaderChange, p.tlsConfig, p.certStore)
+	if err := newPool.Start(p.proxyLoopCtx); err != nil {
+		return err
+	}
+	p.masterPool = newPool
+	p.masterClient = newFailoverMasterClient(newPool)
+	if err := p.repointAllocators(newPool.leaderAddr()); err != nil {
+		return err
+	}
+	oldPool.Close()
+
+	p.logger.Info("proxy reloaded configuration", zap.String("master_addr", newPool.leaderAddr()))
 	return nil
 }
 
-func (p *Proxy) Start() error {
-	return p.startProxy()
+// InFlight reports the number of tasks the scheduler has accepted but not
+// yet finished, so a supervising process can wait for a clean drain point
+// before exiting during a graceful shutdown or live reload.
+func (p *Proxy) InFlight() int64 {
+	return p.sched.InFlight()
 }
 
 func (p *Proxy) stopProxyLoop() {
@@ -185,6 +386,14 @@ func (p *Proxy) stopProxyLoop() {
 
 	p.queryMsgStream.Close()
 
+	if p.masterPool != nil {
+		p.masterPool.Close()
+	}
+
+	if p.metricsServer != nil {
+		_ = p.metricsServer.Stop(context.Background())
+	}
+
 	p.proxyLoopWg.Wait()
 }
 
@@ -195,5 +404,11 @@ func (p *Proxy) Close() {
 	for _, cb := range p.closeCallbacks {
 		cb()
 	}
-	log.Print("proxy closed.")
+
+	if p.tracerShutdown != nil {
+		_ = p.tracerShutdown(context.Background())
+	}
+
+	p.logger.Info("proxy closed")
+	_ = p.logger.Sync()
 }
@@ -0,0 +1,151 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// envInheritedListenerFD names the environment variable a re-exec'd child
+// reads to discover the inherited gRPC listener file descriptor.
+const envInheritedListenerFD = "MILVUS_PROXY_LISTENER_FD"
+
+// Supervisor wraps a Proxy with POSIX signal handling so operators can
+// gracefully drain (SIGTERM/SIGINT), reload config (SIGHUP), or hot-upgrade
+// the binary with zero dropped connections (SIGUSR2) without losing
+// in-flight requests.
+type Supervisor struct {
+	proxy           *Proxy
+	listener        net.Listener
+	drainTimeout    time.Duration
+	drainPollPeriod time.Duration
+	logger          *zap.Logger
+}
+
+// NewSupervisor creates a Supervisor around an already-constructed Proxy.
+// drainTimeout bounds how long shutdown polls for in-flight tasks to finish
+// before it closes the Proxy. Proxy.Close then stops the gRPC server with
+// GracefulStop, which waits for outstanding RPCs with no time limit.
+func NewSupervisor(p *Proxy, drainTimeout time.Duration) *Supervisor {
+	return &Supervisor{
+		proxy:           p,
+		drainTimeout:    drainTimeout,
+		drainPollPeriod: 100 * time.Millisecond,
+		logger:          p.logger,
+	}
+}
+
+// Run binds (or inherits) the gRPC listener, starts the Proxy, and blocks
+// handling signals until a graceful shutdown completes.
+func (s *Supervisor) Run(ctx context.Context) error {
+	lis, err := s.acquireListener()
+	if err != nil {
+		return err
+	}
+	s.listener = lis
+
+	if err := s.proxy.StartWithListener(lis); err != nil {
+		return err
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGTERM, syscall.SIGINT, syscall.SIGHUP, syscall.SIGUSR2)
+	defer signal.Stop(sigCh)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return s.shutdown()
+		case sig := <-sigCh:
+			switch sig {
+			case syscall.SIGTERM, syscall.SIGINT:
+				s.logger.Info("received shutdown signal", zap.Stringer("signal", sig))
+				return s.shutdown()
+			case syscall.SIGHUP:
+				s.logger.Info("received reload signal", zap.Stringer("signal", sig))
+				if err := s.proxy.Reload(); err != nil {
+					s.logger.Error("proxy reload failed", zap.Error(err))
+				}
+			case syscall.SIGUSR2:
+				s.logger.Info("received upgrade signal", zap.Stringer("signal", sig))
+				if err := s.forkExecChild(); err != nil {
+					s.logger.Error("live upgrade failed", zap.Error(err))
+					continue
+				}
+				return s.shutdown()
+			}
+		}
+	}
+}
+
+// acquireListener returns the inherited listener when the process was
+// re-exec'd by a parent during a live upgrade, otherwise it binds a fresh one.
+func (s *Supervisor) acquireListener() (net.Listener, error) {
+	if fdStr := os.Getenv(envInheritedListenerFD); fdStr != "" {
+		var fd uintptr
+		if _, err := fmt.Sscanf(fdStr, "%d", &fd); err != nil {
+			return nil, fmt.Errorf("invalid inherited listener fd %q: %w", fdStr, err)
+		}
+		f := os.NewFile(fd, "inherited-grpc-listener")
+		lis, err := net.FileListener(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inherit listener: %w", err)
+		}
+		return lis, nil
+	}
+
+	// TODO: use address in config instead
+	return net.Listen("tcp", ":5053")
+}
+
+// forkExecChild re-execs the current binary, passing the gRPC listener's file
+// descriptor so the child can rebind it via net.FileListener and take over
+// serving traffic with no dropped connections.
+func (s *Supervisor) forkExecChild() error {
+	f, err := s.listener.(*net.TCPListener).File()
+	if err != nil {
+		return fmt.Errorf("failed to dup listener fd: %w", err)
+	}
+	defer f.Close()
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(exe, os.Args[1:]...)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%d", envInheritedListenerFD, 3))
+	cmd.ExtraFiles = []*os.File{f}
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start child proxy: %w", err)
+	}
+	s.logger.Info("spawned child proxy for live upgrade", zap.Int("pid", cmd.Process.Pid))
+	return nil
+}
+
+// shutdown waits, bounded by drainTimeout, for Proxy.InFlight to reach zero,
+// then closes the Proxy. The gRPC server keeps accepting new calls while it
+// waits and is only stopped by Proxy.Close; msg stream sends are not tracked
+// separately from the tasks counted by InFlight.
+func (s *Supervisor) shutdown() error {
+	deadline := time.Now().Add(s.drainTimeout)
+	for s.proxy.InFlight() > 0 && time.Now().Before(deadline) {
+		time.Sleep(s.drainPollPeriod)
+	}
+	if remaining := s.proxy.InFlight(); remaining > 0 {
+		s.logger.Warn("drain timeout exceeded, closing with tasks still in flight",
+			zap.Int64("in_flight", remaining))
+	}
+	s.proxy.Close()
+	return nil
+}
@@ -0,0 +1,84 @@
+package proxy
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/zilliztech/milvus-distributed/internal/allocator"
+	"github.com/zilliztech/milvus-distributed/internal/proxy/metrics"
+)
+
+// Task is implemented by each gRPC-triggered operation the TaskScheduler
+// dispatches (insert, search, ...). Schedule runs a Task through these three
+// stages in order, mirroring the sched/execute/post-execute pipeline the
+// real handlers build their requests against.
+type Task interface {
+	PreExecute(ctx context.Context) error
+	Execute(ctx context.Context) error
+	PostExecute(ctx context.Context) error
+}
+
+// TaskScheduler accepts requests from the gRPC handlers and runs them through
+// Schedule. It keeps the idAllocator/tsoAllocator it was built with; Schedule
+// does not call them. InFlight is read by the Supervisor to decide when a
+// graceful drain has finished.
+type TaskScheduler struct {
+	ctx          context.Context
+	idAllocator  *allocator.IDAllocator
+	tsoAllocator *allocator.TimestampAllocator
+	logger       *zap.Logger
+
+	inFlight int64
+}
+
+// NewTaskScheduler builds a TaskScheduler bound to ctx's lifetime, logging
+// through lg instead of the package-level log output callers used to get.
+func NewTaskScheduler(ctx context.Context, idAllocator *allocator.IDAllocator, tsoAllocator *allocator.TimestampAllocator, lg *zap.Logger) (*TaskScheduler, error) {
+	return &TaskScheduler{
+		ctx:          ctx,
+		idAllocator:  idAllocator,
+		tsoAllocator: tsoAllocator,
+		logger:       lg,
+	}, nil
+}
+
+func (s *TaskScheduler) Start() {}
+
+func (s *TaskScheduler) Close() {}
+
+// Schedule is the single entry point every gRPC handler dispatches a Task
+// through. It holds t counted in InFlight for the duration of all three
+// stages, so Supervisor.shutdown's drain loop observes real outstanding
+// work instead of a counter nothing ever touches, and times each stage into
+// metrics.SchedulerStageDuration.
+func (s *TaskScheduler) Schedule(ctx context.Context, t Task) error {
+	atomic.AddInt64(&s.inFlight, 1)
+	defer atomic.AddInt64(&s.inFlight, -1)
+
+	stages := []struct {
+		name string
+		run  func(context.Context) error
+	}{
+		{"schedule", t.PreExecute},
+		{"execute", t.Execute},
+		{"post_execute", t.PostExecute},
+	}
+	for _, stage := range stages {
+		start := time.Now()
+		err := stage.run(ctx)
+		metrics.SchedulerStageDuration.WithLabelValues(stage.name).Observe(time.Since(start).Seconds())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InFlight reports the number of tasks accepted but not yet finished, so a
+// supervising process can wait for a clean drain point before exiting.
+func (s *TaskScheduler) InFlight() int64 {
+	return atomic.LoadInt64(&s.inFlight)
+}
@@ -0,0 +1,151 @@
+package proxy
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+)
+
+// TLSConfig configures the Proxy's gRPC surface (server-side) and its
+// connections to master for mutual TLS. Cert/key files are re-read on
+// SIGHUP via Reload, so rotating a cert does not require a restart.
+type TLSConfig struct {
+	CertFile          string
+	KeyFile           string
+	CAFile            string
+	MinVersion        uint16 // e.g. tls.VersionTLS12; defaults to TLS 1.2 when zero
+	RequireClientCert bool
+}
+
+// Enabled reports whether TLS material has been configured at all. When
+// false, the Proxy falls back to the previous cleartext behavior.
+func (cfg TLSConfig) Enabled() bool {
+	return cfg.CertFile != "" && cfg.KeyFile != ""
+}
+
+func (cfg TLSConfig) minVersion() uint16 {
+	if cfg.MinVersion == 0 {
+		return tls.VersionTLS12
+	}
+	return cfg.MinVersion
+}
+
+// certStore holds the currently active certificate/CA pool behind a mutex so
+// it can be rotated by Reload without racing in-flight TLS handshakes, which
+// read it through GetCertificate/GetConfigForClient callbacks.
+type certStore struct {
+	mu   sync.RWMutex
+	cert *tls.Certificate
+	pool *x509.CertPool
+}
+
+func newCertStore() *certStore {
+	return &certStore{}
+}
+
+// load reads cfg's cert/key/CA files, refusing to install them if the leaf
+// certificate is expired, signed with a deprecated algorithm, or if
+// RequireClientCert is set without a CA bundle to verify client certs
+// against.
+func (s *certStore) load(cfg TLSConfig) error {
+	if cfg.RequireClientCert && cfg.CAFile == "" {
+		return fmt.Errorf("refusing to start: RequireClientCert is set but no CAFile was configured to verify client certs against")
+	}
+
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS cert/key: %w", err)
+	}
+	if err := checkCertHealthy(cert); err != nil {
+		return err
+	}
+
+	var pool *x509.CertPool
+	if cfg.CAFile != "" {
+		caBytes, err := ioutil.ReadFile(cfg.CAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caBytes) {
+			return fmt.Errorf("failed to parse CA bundle %s", cfg.CAFile)
+		}
+	}
+
+	s.mu.Lock()
+	s.cert = &cert
+	s.pool = pool
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *certStore) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.cert == nil {
+		return nil, fmt.Errorf("no TLS certificate loaded")
+	}
+	return s.cert, nil
+}
+
+func (s *certStore) getClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return s.getCertificate(nil)
+}
+
+func (s *certStore) certPool() *x509.CertPool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.pool
+}
+
+// checkCertHealthy refuses to boot with an expired leaf certificate or one
+// signed with a deprecated algorithm, the prerequisite check for running the
+// Proxy in any real multi-tenant deployment.
+func checkCertHealthy(cert tls.Certificate) error {
+	if len(cert.Certificate) == 0 {
+		return fmt.Errorf("certificate chain is empty")
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return fmt.Errorf("failed to parse leaf certificate: %w", err)
+	}
+	if time.Now().After(leaf.NotAfter) {
+		return fmt.Errorf("certificate expired at %s", leaf.NotAfter)
+	}
+	switch leaf.SignatureAlgorithm {
+	case x509.MD5WithRSA, x509.SHA1WithRSA, x509.DSAWithSHA1, x509.ECDSAWithSHA1:
+		return fmt.Errorf("certificate uses deprecated signature algorithm %s", leaf.SignatureAlgorithm)
+	}
+	return nil
+}
+
+// serverTLSConfig builds the *tls.Config passed to credentials.NewTLS for
+// grpcLoop. GetConfigForClient re-reads the cert store on every handshake so
+// a SIGHUP-triggered Reload takes effect without restarting the listener.
+func (cfg TLSConfig) serverTLSConfig(store *certStore) *tls.Config {
+	return &tls.Config{
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			inner := &tls.Config{
+				GetCertificate: store.getCertificate,
+				MinVersion:     cfg.minVersion(),
+			}
+			if cfg.RequireClientCert {
+				inner.ClientAuth = tls.RequireAndVerifyClientCert
+				inner.ClientCAs = store.certPool()
+			}
+			return inner, nil
+		},
+	}
+}
+
+// clientTLSConfig builds the *tls.Config used when dialing master.
+func (cfg TLSConfig) clientTLSConfig(store *certStore) *tls.Config {
+	return &tls.Config{
+		GetClientCertificate: store.getClientCertificate,
+		RootCAs:              store.certPool(),
+		MinVersion:           cfg.minVersion(),
+	}
+}
@@ -0,0 +1,94 @@
+package proxy
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func selfSignedCert(t *testing.T, notBefore, notAfter time.Time) tls.Certificate {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "proxy-test"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestCheckCertHealthyRejectsExpired(t *testing.T) {
+	cert := selfSignedCert(t, time.Now().Add(-48*time.Hour), time.Now().Add(-24*time.Hour))
+
+	if err := checkCertHealthy(cert); err == nil {
+		t.Fatal("checkCertHealthy accepted an expired certificate")
+	}
+}
+
+func TestCheckCertHealthyAcceptsValid(t *testing.T) {
+	cert := selfSignedCert(t, time.Now().Add(-time.Hour), time.Now().Add(24*time.Hour))
+
+	if err := checkCertHealthy(cert); err != nil {
+		t.Fatalf("checkCertHealthy rejected a valid certificate: %v", err)
+	}
+}
+
+func TestTLSConfigEnabled(t *testing.T) {
+	cases := []struct {
+		name string
+		cfg  TLSConfig
+		want bool
+	}{
+		{"empty", TLSConfig{}, false},
+		{"cert only", TLSConfig{CertFile: "cert.pem"}, false},
+		{"cert and key", TLSConfig{CertFile: "cert.pem", KeyFile: "key.pem"}, true},
+	}
+
+	for _, c := range cases {
+		if got := c.cfg.Enabled(); got != c.want {
+			t.Errorf("%s: Enabled() = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestTLSConfigMinVersionDefault(t *testing.T) {
+	var cfg TLSConfig
+	if got := cfg.minVersion(); got != tls.VersionTLS12 {
+		t.Errorf("minVersion() = %v, want TLS 1.2 default", got)
+	}
+
+	cfg.MinVersion = tls.VersionTLS13
+	if got := cfg.minVersion(); got != tls.VersionTLS13 {
+		t.Errorf("minVersion() = %v, want the configured override", got)
+	}
+}
+
+func TestCertStoreLoadRejectsRequireClientCertWithoutCA(t *testing.T) {
+	s := newCertStore()
+	cfg := TLSConfig{
+		CertFile:          "cert.pem",
+		KeyFile:           "key.pem",
+		RequireClientCert: true,
+	}
+
+	if err := s.load(cfg); err == nil {
+		t.Fatal("load accepted RequireClientCert without a CAFile")
+	}
+}
@@ -0,0 +1,53 @@
+package proxy
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/zilliztech/milvus-distributed/internal/proxy"
+
+// initTracer installs a process-wide OpenTelemetry tracer provider and
+// returns a Tracer for the Proxy to start spans with, plus a shutdown func
+// to flush on Close. Spans are batched to an OTLP collector at endpoint, or,
+// when endpoint is empty, to stdout so local development has something to
+// look at without standing up a collector. Since TaskScheduler.Schedule and
+// MsgStream.Produce both thread the ctx a handler span is started on, a span
+// started with the returned Tracer propagates through TaskScheduler into the
+// msg stream publish.
+func initTracer(endpoint string) (trace.Tracer, func(context.Context) error, error) {
+	res, err := resource.New(context.Background(),
+		resource.WithAttributes(semconv.ServiceNameKey.String("milvus-proxy")),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var exporter sdktrace.SpanExporter
+	if endpoint != "" {
+		exporter, err = otlptracegrpc.New(context.Background(),
+			otlptracegrpc.WithEndpoint(endpoint),
+			otlptracegrpc.WithInsecure(),
+		)
+	} else {
+		exporter, err = stdouttrace.New(stdouttrace.WithoutTimestamps())
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithResource(res),
+		sdktrace.WithBatcher(exporter),
+	)
+	otel.SetTracerProvider(tp)
+
+	return tp.Tracer(tracerName), tp.Shutdown, nil
+}